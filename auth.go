@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	sessionCookieName = "gb_session"
+	oauthStateCookie  = "gb_oauth_state"
+	sessionDuration   = 24 * time.Hour
+)
+
+var oauthConfig = &oauth2.Config{
+	ClientID:     os.Getenv("GOOGLE_OAUTH_CLIENT_ID"),
+	ClientSecret: os.Getenv("GOOGLE_OAUTH_CLIENT_SECRET"),
+	RedirectURL:  os.Getenv("GOOGLE_OAUTH_REDIRECT_URL"),
+	Scopes:       []string{"email"},
+	Endpoint:     google.Endpoint,
+}
+
+type session struct {
+	email     string
+	expiresAt time.Time
+}
+
+var (
+	sessionsMu sync.Mutex
+	sessions   = map[string]session{}
+)
+
+type contextKey string
+
+const userEmailContextKey contextKey = "userEmail"
+
+// handleAuthLogin starts the OAuth2 flow by stashing an anti-CSRF state
+// value in a short-lived cookie and redirecting to Google's consent page.
+func handleAuthLogin(w http.ResponseWriter, r *http.Request) {
+	state, err := randomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   int((10 * time.Minute).Seconds()),
+	})
+
+	http.Redirect(w, r, oauthConfig.AuthCodeURL(state), http.StatusFound)
+}
+
+// handleAuthCallback exchanges the authorization code for a token, looks up
+// the signed-in user's email, and stores that email behind a session
+// cookie. The token itself is not kept once the email has been fetched.
+func handleAuthCallback(w http.ResponseWriter, r *http.Request) {
+	stateCookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || r.URL.Query().Get("state") != stateCookie.Value {
+		http.Error(w, "invalid oauth state", http.StatusBadRequest)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	token, err := oauthConfig.Exchange(r.Context(), code)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("exchanging code: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	email, err := fetchGoogleEmail(r.Context(), token)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("fetching user info: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	sessionID, err := randomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sessionsMu.Lock()
+	sessions[sessionID] = session{email: email, expiresAt: time.Now().Add(sessionDuration)}
+	sessionsMu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    sessionID,
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   int(sessionDuration.Seconds()),
+	})
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// fetchGoogleEmail calls Google's userinfo endpoint with the freshly
+// exchanged token to learn which account just logged in.
+func fetchGoogleEmail(ctx context.Context, token *oauth2.Token) (string, error) {
+	client := oauthConfig.Client(ctx, token)
+
+	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", err
+	}
+
+	if info.Email == "" {
+		return "", fmt.Errorf("userinfo response missing email")
+	}
+
+	return info.Email, nil
+}
+
+// requireAuth rejects requests without a valid session cookie and injects
+// the authenticated email into the request context for downstream
+// handlers to read via userEmailFromContext.
+func requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookieName)
+		if err != nil {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+
+		sessionsMu.Lock()
+		sess, ok := sessions[cookie.Value]
+		sessionsMu.Unlock()
+
+		if !ok || time.Now().After(sess.expiresAt) {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userEmailContextKey, sess.email)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func userEmailFromContext(ctx context.Context) (string, bool) {
+	email, ok := ctx.Value(userEmailContextKey).(string)
+	return email, ok
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
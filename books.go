@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/mateoferrari97/GoogleBooks/filter"
+)
+
+const (
+	maxLimit           = 50
+	maxResultsPerPage  = 40
+	maxConcurrentPages = 4
+)
+
+// bookScorer decides whether a book passes the configured filter rules and
+// what score it gets, replacing the old hard-coded hasEmptyInformation
+// drop. It's initialized in main from FILTER_CONFIG, falling back to
+// filter.Default().
+var bookScorer = filter.Default()
+
+type googleBooksPage struct {
+	TotalItems int `json:"totalItems"`
+	Items      []struct {
+		BookInformation BookInformation `json:"volumeInfo"`
+	} `json:"items"`
+}
+
+// scannedBook pairs a book that passed bookScorer with the absolute
+// Google Books startIndex it was found at, so getBooks can report a
+// nextOffset a caller can resume from without re-scanning or skipping
+// anything the scorer dropped along the way.
+type scannedBook struct {
+	book  Book
+	index int
+}
+
+// getBooks pages through the Google Books API for query starting at offset,
+// returning up to limit filtered results, the upstream totalItems count, and
+// the startIndex a caller should resume from for the next page. Pages are
+// fanned out across a bounded pool of concurrent workers instead of being
+// fetched one at a time.
+func getBooks(ctx context.Context, query string, offset, limit int) ([]Book, int, int, error) {
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	if limit == 0 {
+		return []Book{}, 0, offset, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, upstreamTimeout())
+	defer cancel()
+
+	q := url.QueryEscape(query)
+
+	var (
+		candidates []scannedBook
+		totalItems int
+		start      = offset
+	)
+
+	for len(candidates) < limit {
+		starts := pageStarts(start, limit-len(candidates))
+
+		pages, err := fetchPages(ctx, q, starts)
+		if err != nil {
+			return []Book{}, 0, offset, err
+		}
+
+		if len(pages) > 0 {
+			totalItems = pages[0].TotalItems
+		}
+
+		exhausted := len(pages) == 0
+		for pi, page := range pages {
+			if len(page.Items) < maxResultsPerPage {
+				exhausted = true
+			}
+
+			for ii, item := range page.Items {
+				score, passes := bookScorer.Score(toFilterBook(item.BookInformation))
+				if !passes {
+					continue
+				}
+
+				candidates = append(candidates, scannedBook{
+					book:  Book{BookInformation: item.BookInformation, Score: score},
+					index: starts[pi] + ii,
+				})
+			}
+		}
+
+		start += len(starts) * maxResultsPerPage
+
+		if exhausted {
+			break
+		}
+	}
+
+	nextOffset := start
+
+	books := make([]Book, len(candidates))
+	for i, c := range candidates {
+		books[i] = c.book
+	}
+
+	if len(books) > limit {
+		nextOffset = candidates[limit-1].index + 1
+		books = books[:limit]
+	}
+
+	return books, totalItems, nextOffset, nil
+}
+
+// pageStarts computes the startIndex values needed to cover `need` items
+// starting at `from`, capped at maxConcurrentPages windows per round.
+func pageStarts(from, need int) []int {
+	pages := (need + maxResultsPerPage - 1) / maxResultsPerPage
+	if pages > maxConcurrentPages {
+		pages = maxConcurrentPages
+	}
+	if pages == 0 {
+		pages = 1
+	}
+
+	starts := make([]int, pages)
+	for i := range starts {
+		starts[i] = from + i*maxResultsPerPage
+	}
+
+	return starts
+}
+
+// fetchPages fans the given startIndex values out across a bounded errgroup
+// and returns the decoded pages back in request order.
+func fetchPages(ctx context.Context, escapedQuery string, starts []int) ([]googleBooksPage, error) {
+	pages := make([]googleBooksPage, len(starts))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentPages)
+
+	for i, start := range starts {
+		i, start := i, start
+		g.Go(func() error {
+			page, err := fetchPage(ctx, escapedQuery, start)
+			if err != nil {
+				return err
+			}
+
+			pages[i] = page
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// fetchPage fetches a single startIndex window from the Google Books API.
+func fetchPage(ctx context.Context, escapedQuery string, start int) (googleBooksPage, error) {
+	resp, err := doUpstreamGET(ctx, fmt.Sprintf(
+		"https://www.googleapis.com/books/v1/volumes?q=%s&startIndex=%d&maxResults=%d",
+		escapedQuery, start, maxResultsPerPage,
+	))
+	if err != nil {
+		return googleBooksPage{}, fmt.Errorf("making GET request: %v", err)
+	}
+
+	var page googleBooksPage
+
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	resp.Body.Close()
+	if err != nil {
+		return googleBooksPage{}, err
+	}
+
+	return page, nil
+}
+
+// filterByMinScore drops any book scoring below minScore.
+func filterByMinScore(books []Book, minScore float64) []Book {
+	if minScore <= 0 {
+		return books
+	}
+
+	filtered := books[:0]
+	for _, book := range books {
+		if book.Score >= minScore {
+			filtered = append(filtered, book)
+		}
+	}
+
+	return filtered
+}
+
+// sortBooks orders books in place by the given field: "score" (descending,
+// the default), "title", or "pages" (descending).
+func sortBooks(books []Book, by string) {
+	switch by {
+	case "title":
+		sort.Slice(books, func(i, j int) bool {
+			return books[i].BookInformation.Title < books[j].BookInformation.Title
+		})
+	case "pages":
+		sort.Slice(books, func(i, j int) bool {
+			return books[i].BookInformation.Pages > books[j].BookInformation.Pages
+		})
+	default:
+		sort.Slice(books, func(i, j int) bool {
+			return books[i].Score > books[j].Score
+		})
+	}
+}
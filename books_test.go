@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPageStarts(t *testing.T) {
+	tests := []struct {
+		name string
+		from int
+		need int
+		want []int
+	}{
+		{name: "single partial page", from: 0, need: 10, want: []int{0}},
+		{name: "exact page boundary", from: 0, need: maxResultsPerPage, want: []int{0}},
+		{name: "spans two pages", from: 0, need: maxResultsPerPage + 1, want: []int{0, maxResultsPerPage}},
+		{
+			name: "capped at maxConcurrentPages windows",
+			from: 0,
+			need: maxResultsPerPage * (maxConcurrentPages + 5),
+			want: []int{0, maxResultsPerPage, maxResultsPerPage * 2, maxResultsPerPage * 3},
+		},
+		{name: "zero need still returns one window", from: 20, need: 0, want: []int{20}},
+		{name: "resumes from a non-zero offset", from: 40, need: 10, want: []int{40}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pageStarts(tt.from, tt.need)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("pageStarts(%d, %d) = %v, want %v", tt.from, tt.need, got, tt.want)
+			}
+		})
+	}
+}
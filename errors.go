@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Typed errors returned by the lookup helpers so handlers can map them to
+// the right HTTP status instead of collapsing everything to a 500.
+var (
+	ErrNotFound   = errors.New("book not found")
+	ErrUpstream   = errors.New("upstream request failed")
+	ErrBadRequest = errors.New("bad request")
+)
+
+// statusFor maps a typed error to the HTTP status code a handler should
+// respond with, falling back to 500 for anything unrecognized.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUpstream):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleConfig describes one entry of a FILTER_CONFIG file. Field selects
+// which built-in rule to instantiate; the remaining fields only apply to
+// the rules that use them.
+type RuleConfig struct {
+	Field               string   `json:"field" yaml:"field"`
+	Required            bool     `json:"required" yaml:"required"`
+	MinPages            int      `json:"min_pages,omitempty" yaml:"min_pages,omitempty"`
+	Languages           []string `json:"languages,omitempty" yaml:"languages,omitempty"`
+	BlacklistCategories []string `json:"blacklist_categories,omitempty" yaml:"blacklist_categories,omitempty"`
+}
+
+// Config is the top-level shape of a FILTER_CONFIG file.
+type Config struct {
+	Rules []RuleConfig `json:"rules" yaml:"rules"`
+}
+
+// LoadConfig reads a FILTER_CONFIG file (YAML or JSON, selected by
+// extension) and builds the Scorer it describes.
+func LoadConfig(path string) (*Scorer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading filter config: %w", err)
+	}
+
+	var cfg Config
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		return nil, fmt.Errorf("unsupported filter config extension %q", ext)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing filter config: %w", err)
+	}
+
+	rules, err := buildRules(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewScorer(rules), nil
+}
+
+// Default returns the Scorer matching the module's historical behavior:
+// title, description, authors, categories, both images, and pages are all
+// required and non-empty, mirroring the old hasEmptyInformation checks.
+func Default() *Scorer {
+	return NewScorer([]Rule{
+		titleRule(true),
+		descriptionRule(true),
+		authorsRule(true),
+		categoriesRule(true),
+		imageSmallRule(true),
+		imageNormalRule(true),
+		minPagesRule{required: true, min: 1},
+	})
+}
+
+func buildRules(configs []RuleConfig) ([]Rule, error) {
+	rules := make([]Rule, 0, len(configs))
+
+	for _, c := range configs {
+		switch c.Field {
+		case "title":
+			rules = append(rules, titleRule(c.Required))
+		case "description":
+			rules = append(rules, descriptionRule(c.Required))
+		case "authors":
+			rules = append(rules, authorsRule(c.Required))
+		case "categories":
+			rules = append(rules, categoriesRule(c.Required))
+		case "image_small":
+			rules = append(rules, imageSmallRule(c.Required))
+		case "image_normal":
+			rules = append(rules, imageNormalRule(c.Required))
+		case "min_pages":
+			rules = append(rules, minPagesRule{required: c.Required, min: c.MinPages})
+		case "language":
+			rules = append(rules, languageRule{required: c.Required, allowed: toSet(c.Languages)})
+		case "category_blacklist":
+			rules = append(rules, categoryBlacklistRule{required: c.Required, blacklist: toSet(c.BlacklistCategories)})
+		default:
+			return nil, fmt.Errorf("unknown filter rule field %q", c.Field)
+		}
+	}
+
+	return rules, nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+
+	return set
+}
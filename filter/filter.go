@@ -0,0 +1,61 @@
+// Package filter scores and filters Google Books results, replacing the
+// old hard-coded hasEmptyInformation drop with configurable rules an
+// operator can tune without a code change.
+package filter
+
+// Book is the subset of a Google Books volume a Rule can see. It mirrors
+// main.BookInformation without importing it, so this package stays free
+// of a dependency back on the module root.
+type Book struct {
+	Title       string
+	Description string
+	Authors     []string
+	Categories  []string
+	Pages       int
+	Language    string
+	ImageSmall  string
+	ImageNormal string
+}
+
+// Rule scores a Book and, if Required reports true, can hard-drop it when
+// that score is zero.
+type Rule interface {
+	Score(b Book) float64
+	Required() bool
+}
+
+// Scorer aggregates a set of rules into a single per-book score, and
+// reports whether the book passes every required rule.
+type Scorer struct {
+	rules []Rule
+}
+
+// NewScorer builds a Scorer from the given rules. A nil or empty rule set
+// scores every book 1 and never drops anything.
+func NewScorer(rules []Rule) *Scorer {
+	return &Scorer{rules: rules}
+}
+
+// Score returns the mean score across all rules, and passes, which is
+// false if any required rule scored zero. A book that fails a required
+// rule still gets a score, so operators can see how close it came instead
+// of it silently disappearing.
+func (s *Scorer) Score(b Book) (score float64, passes bool) {
+	if len(s.rules) == 0 {
+		return 1, true
+	}
+
+	passes = true
+
+	var total float64
+	for _, rule := range s.rules {
+		sc := rule.Score(b)
+		total += sc
+
+		if rule.Required() && sc == 0 {
+			passes = false
+		}
+	}
+
+	return total / float64(len(s.rules)), passes
+}
@@ -0,0 +1,76 @@
+package filter
+
+import "testing"
+
+func TestScorerScoreNoRules(t *testing.T) {
+	s := NewScorer(nil)
+
+	score, passes := s.Score(Book{})
+	if score != 1 || !passes {
+		t.Fatalf("got score=%v passes=%v, want score=1 passes=true", score, passes)
+	}
+}
+
+func TestScorerScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      []Rule
+		book       Book
+		wantScore  float64
+		wantPasses bool
+	}{
+		{
+			name:       "all rules pass",
+			rules:      []Rule{titleRule(true), minPagesRule{required: true, min: 1}},
+			book:       Book{Title: "Dune", Pages: 412},
+			wantScore:  1,
+			wantPasses: true,
+		},
+		{
+			name:       "required rule fails drops passes but keeps score",
+			rules:      []Rule{titleRule(true), descriptionRule(false)},
+			book:       Book{Title: ""},
+			wantScore:  0,
+			wantPasses: false,
+		},
+		{
+			name:       "optional rule fails lowers score without failing passes",
+			rules:      []Rule{titleRule(true), descriptionRule(false)},
+			book:       Book{Title: "Dune"},
+			wantScore:  0.5,
+			wantPasses: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, passes := NewScorer(tt.rules).Score(tt.book)
+			if score != tt.wantScore || passes != tt.wantPasses {
+				t.Fatalf("got score=%v passes=%v, want score=%v passes=%v", score, passes, tt.wantScore, tt.wantPasses)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	complete := Book{
+		Title:       "Dune",
+		Description: "A desert planet",
+		Authors:     []string{"Frank Herbert"},
+		Categories:  []string{"Science Fiction"},
+		Pages:       412,
+		ImageSmall:  "small.jpg",
+		ImageNormal: "normal.jpg",
+	}
+
+	if _, passes := Default().Score(complete); !passes {
+		t.Fatalf("expected a fully populated book to pass the default rules")
+	}
+
+	incomplete := complete
+	incomplete.Description = ""
+
+	if _, passes := Default().Score(incomplete); passes {
+		t.Fatalf("expected a book missing its description to fail the default rules")
+	}
+}
@@ -0,0 +1,95 @@
+package filter
+
+// presenceRule scores 1 when a field is non-empty and 0 otherwise. It
+// backs the title/description/authors/categories/image checks that used
+// to be hard-coded in hasEmptyInformation.
+type presenceRule struct {
+	required bool
+	present  func(Book) bool
+}
+
+func (r presenceRule) Score(b Book) float64 {
+	if r.present(b) {
+		return 1
+	}
+
+	return 0
+}
+
+func (r presenceRule) Required() bool { return r.required }
+
+func titleRule(required bool) Rule {
+	return presenceRule{required: required, present: func(b Book) bool { return b.Title != "" }}
+}
+
+func descriptionRule(required bool) Rule {
+	return presenceRule{required: required, present: func(b Book) bool { return b.Description != "" }}
+}
+
+func authorsRule(required bool) Rule {
+	return presenceRule{required: required, present: func(b Book) bool { return len(b.Authors) > 0 }}
+}
+
+func categoriesRule(required bool) Rule {
+	return presenceRule{required: required, present: func(b Book) bool { return len(b.Categories) > 0 }}
+}
+
+func imageSmallRule(required bool) Rule {
+	return presenceRule{required: required, present: func(b Book) bool { return b.ImageSmall != "" }}
+}
+
+func imageNormalRule(required bool) Rule {
+	return presenceRule{required: required, present: func(b Book) bool { return b.ImageNormal != "" }}
+}
+
+// minPagesRule scores 1 when a book has at least min pages.
+type minPagesRule struct {
+	required bool
+	min      int
+}
+
+func (r minPagesRule) Score(b Book) float64 {
+	if b.Pages >= r.min {
+		return 1
+	}
+
+	return 0
+}
+
+func (r minPagesRule) Required() bool { return r.required }
+
+// languageRule scores 1 when a book's language is in the allowed set, or
+// when no set was configured.
+type languageRule struct {
+	required bool
+	allowed  map[string]bool
+}
+
+func (r languageRule) Score(b Book) float64 {
+	if len(r.allowed) == 0 || r.allowed[b.Language] {
+		return 1
+	}
+
+	return 0
+}
+
+func (r languageRule) Required() bool { return r.required }
+
+// categoryBlacklistRule scores 0 if any of a book's categories is on the
+// blacklist, 1 otherwise.
+type categoryBlacklistRule struct {
+	required  bool
+	blacklist map[string]bool
+}
+
+func (r categoryBlacklistRule) Score(b Book) float64 {
+	for _, category := range b.Categories {
+		if r.blacklist[category] {
+			return 0
+		}
+	}
+
+	return 1
+}
+
+func (r categoryBlacklistRule) Required() bool { return r.required }
@@ -0,0 +1,69 @@
+package filter
+
+import "testing"
+
+func TestLanguageRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		lang    string
+		want    float64
+	}{
+		{name: "no allowlist passes anything", allowed: nil, lang: "fr", want: 1},
+		{name: "allowed language", allowed: []string{"en", "es"}, lang: "en", want: 1},
+		{name: "disallowed language", allowed: []string{"en", "es"}, lang: "fr", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := languageRule{allowed: toSet(tt.allowed)}
+			if got := r.Score(Book{Language: tt.lang}); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategoryBlacklistRule(t *testing.T) {
+	r := categoryBlacklistRule{blacklist: toSet([]string{"Erotica"})}
+
+	tests := []struct {
+		name       string
+		categories []string
+		want       float64
+	}{
+		{name: "no categories", categories: nil, want: 1},
+		{name: "clean categories", categories: []string{"Fiction", "Adventure"}, want: 1},
+		{name: "blacklisted category", categories: []string{"Fiction", "Erotica"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Score(Book{Categories: tt.categories}); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinPagesRule(t *testing.T) {
+	r := minPagesRule{min: 100}
+
+	tests := []struct {
+		name  string
+		pages int
+		want  float64
+	}{
+		{name: "below minimum", pages: 50, want: 0},
+		{name: "at minimum", pages: 100, want: 1},
+		{name: "above minimum", pages: 200, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Score(Book{Pages: tt.pages}); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
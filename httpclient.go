@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// httpClient is shared by every upstream call so connections get reused
+// instead of opening a fresh socket (and exhausting file descriptors) per
+// request.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConnsPerHost: 20,
+		IdleConnTimeout:     90 * time.Second,
+		DisableCompression:  false,
+	},
+}
+
+const maxUpstreamRetries = 3
+
+// upstreamTimeout returns the per-request timeout applied to upstream
+// calls, configurable via UPSTREAM_TIMEOUT (e.g. "5s"), defaulting to 5s.
+func upstreamTimeout() time.Duration {
+	if v := os.Getenv("UPSTREAM_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+
+	return 5 * time.Second
+}
+
+// doUpstreamGET issues a GET against an upstream API under ctx, retrying
+// network errors and 5xx responses with exponential backoff and jitter, for
+// at most maxUpstreamRetries attempts in total (the first one included). A 429 response waits out Retry-After
+// instead of the usual backoff schedule. The caller must close the
+// response body on success.
+func doUpstreamGET(ctx context.Context, url string) (*http.Response, error) {
+	var (
+		lastErr     error
+		rateLimited bool
+	)
+
+	for attempt := 0; attempt < maxUpstreamRetries; attempt++ {
+		if attempt > 0 && !rateLimited {
+			if err := sleep(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+		rateLimited = false
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("upstream rate limited")
+			rateLimited = true
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = fmt.Errorf("upstream returned %d", resp.StatusCode)
+			resp.Body.Close()
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUpstream, lastErr)
+}
+
+// backoff returns an exponential delay (100ms base) with full jitter for
+// the given retry attempt, starting at attempt 1.
+func backoff(attempt int) time.Duration {
+	base := time.Duration(math.Pow(2, float64(attempt))) * 100 * time.Millisecond
+	return time.Duration(rand.Int63n(int64(base)))
+}
+
+// retryAfter parses a Retry-After header in either form RFC 7231 allows -
+// delay-seconds ("120") or an HTTP-date ("Fri, 31 Dec 1999 23:59:59 GMT") -
+// falling back to one second when the header is absent or unparseable.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return time.Second
+	}
+
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if when, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return wait
+		}
+
+		return 0
+	}
+
+	return time.Second
+}
+
+// sleep waits for d or returns ctx.Err() if ctx is canceled first.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// isbnPattern matches the ISBN-10 and ISBN-13 shapes Google Books' "q=isbn:"
+// search expects: 10 digits (the last may be the "X" check digit) or 13
+// digits.
+var isbnPattern = regexp.MustCompile(`^(?:\d{9}[\dXx]|\d{13})$`)
+
+// getBookByISBN looks up a single book by ISBN against the Google Books
+// "q=isbn:" search, falling back to OpenLibrary to fill in anything Google
+// Books left empty.
+func getBookByISBN(ctx context.Context, isbn string) (Book, error) {
+	if !isbnPattern.MatchString(isbn) {
+		return Book{}, fmt.Errorf("%w: malformed isbn %q", ErrBadRequest, isbn)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, upstreamTimeout())
+	defer cancel()
+
+	q := url.QueryEscape(fmt.Sprintf("isbn:%s", isbn))
+
+	resp, err := doUpstreamGET(ctx, fmt.Sprintf("https://www.googleapis.com/books/v1/volumes?q=%s", q))
+	if err != nil {
+		return Book{}, fmt.Errorf("making GET request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Items []struct {
+			BookInformation BookInformation `json:"volumeInfo"`
+		} `json:"items"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return Book{}, fmt.Errorf("%w: decoding response: %v", ErrUpstream, err)
+	}
+
+	if len(response.Items) == 0 {
+		return Book{}, fmt.Errorf("%w: no book for isbn %q", ErrNotFound, isbn)
+	}
+
+	info := response.Items[0].BookInformation
+
+	score, passes := bookScorer.Score(toFilterBook(info))
+	if !passes {
+		info = enrichFromOpenLibrary(ctx, isbn, info)
+		score, _ = bookScorer.Score(toFilterBook(info))
+	}
+
+	return Book{BookInformation: info, Score: score}, nil
+}
+
+// getBookByVolumeID looks up a single book directly by its Google Books
+// volume ID, falling back to OpenLibrary in the same way as getBookByISBN.
+func getBookByVolumeID(ctx context.Context, id string) (Book, error) {
+	ctx, cancel := context.WithTimeout(ctx, upstreamTimeout())
+	defer cancel()
+
+	resp, err := doUpstreamGET(ctx, fmt.Sprintf("https://www.googleapis.com/books/v1/volumes/%s", url.PathEscape(id)))
+	if err != nil {
+		return Book{}, fmt.Errorf("making GET request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Book{}, fmt.Errorf("%w: no book for volume id %q", ErrNotFound, id)
+	}
+
+	var response struct {
+		BookInformation BookInformation `json:"volumeInfo"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return Book{}, fmt.Errorf("%w: decoding response: %v", ErrUpstream, err)
+	}
+
+	info := response.BookInformation
+	if info.Title == "" {
+		return Book{}, fmt.Errorf("%w: no book for volume id %q", ErrNotFound, id)
+	}
+
+	score, passes := bookScorer.Score(toFilterBook(info))
+	if !passes {
+		if isbn := isbnFromBookInformation(info); isbn != "" {
+			info = enrichFromOpenLibrary(ctx, isbn, info)
+			score, _ = bookScorer.Score(toFilterBook(info))
+		}
+	}
+
+	return Book{BookInformation: info, Score: score}, nil
+}
@@ -4,34 +4,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
 	"strconv"
 
 	"github.com/gorilla/mux"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+
+	"github.com/mateoferrari97/GoogleBooks/filter"
 )
 
-const maxLimit = 50
+var shelfStore ShelfStore
 
-type Book struct {
-	BookInformation BookInformation `json:"book_information"`
-}
+func main() {
+	if path := os.Getenv("FILTER_CONFIG"); path != "" {
+		scorer, err := filter.LoadConfig(path)
+		if err != nil {
+			panic(err)
+		}
+		bookScorer = scorer
+	}
 
-type BookInformation struct {
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Authors     []string `json:"authors"`
-	Categories  []string `json:"categories"`
-	Pages       int      `json:"pageCount"`
-	Images      struct {
-		Small  string `json:"smallThumbnail"`
-		Normal string `json:"thumbnail"`
-	} `json:"imageLinks"`
-}
+	if err := initMeili(); err != nil {
+		panic(err)
+	}
+
+	store, err := newShelfStore()
+	if err != nil {
+		panic(err)
+	}
+	shelfStore = store
 
-func main() {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
@@ -63,20 +64,74 @@ func main() {
 			return
 		}
 
-		books, err := getBooks(query, l)
+		o := 0
+		if offset := r.URL.Query().Get("offset"); offset != "" {
+			o, err = strconv.Atoi(offset)
+			if err != nil || o < 0 {
+				http.Error(w, "invalid offset", http.StatusBadRequest)
+				return
+			}
+		}
+
+		minScore := 0.0
+		if ms := r.URL.Query().Get("min_score"); ms != "" {
+			minScore, err = strconv.ParseFloat(ms, 64)
+			if err != nil {
+				http.Error(w, "invalid min_score", http.StatusBadRequest)
+				return
+			}
+		}
+
+		sortBy := r.URL.Query().Get("sort")
+		if sortBy == "" {
+			sortBy = "score"
+		}
+		if sortBy != "score" && sortBy != "title" && sortBy != "pages" {
+			http.Error(w, "sort must be one of score, title, pages", http.StatusBadRequest)
+			return
+		}
+
+		var (
+			books      []Book
+			total      int
+			facets     map[string]map[string]int64
+			nextOffset int
+		)
+
+		if meiliClient != nil {
+			books, facets, _, err = searchIndexedBooks(query, o, l)
+			total = len(books)
+			nextOffset = o + len(books)
+		}
+
+		if len(books) == 0 {
+			books, total, nextOffset, err = getBooks(r.Context(), query, o, l)
+		}
+
 		if err != nil {
 			http.Error(w, fmt.Sprintf("getting books: %v", err), http.StatusInternalServerError)
 			return
 		}
 
+		books = filterByMinScore(books, minScore)
+		sortBooks(books, sortBy)
+
 		response := struct {
-			Query string `json:"query"`
-			Total int    `json:"total"`
-			Books []Book `json:"books"`
+			Query      string                      `json:"query"`
+			Total      int                         `json:"total"`
+			Offset     int                         `json:"offset"`
+			Limit      int                         `json:"limit"`
+			NextOffset int                         `json:"next_offset"`
+			Books      []Book                      `json:"books"`
+			Facets     map[string]map[string]int64 `json:"facets,omitempty"`
 		}{
-			Query: query,
-			Total: len(books),
-			Books: books,
+			Query:      query,
+			Total:      total,
+			Offset:     o,
+			Limit:      l,
+			NextOffset: nextOffset,
+			Books:      books,
+			Facets:     facets,
 		}
 
 		v, err := json.Marshal(&response)
@@ -89,74 +144,193 @@ func main() {
 		w.Write(v)
 	}).Methods("GET")
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	if err := http.ListenAndServe(":" + port, router); err != nil {
-		panic(err)
-	}
-}
+	router.HandleFunc("/books/isbn/{isbn}", func(w http.ResponseWriter, r *http.Request) {
+		isbn := mux.Vars(r)["isbn"]
+		if isbn == "" {
+			http.Error(w, "isbn is required", http.StatusBadRequest)
+			return
+		}
 
-func getBooks(query string, limit int) ([]Book, error) {
-	var books []Book
+		book, err := getBookByISBN(r.Context(), isbn)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("getting book by isbn: %v", err), statusFor(err))
+			return
+		}
 
-	q := url.QueryEscape(query)
+		v, err := json.Marshal(&book)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-	if limit > maxLimit {
-		limit = maxLimit
-	}
+		w.WriteHeader(http.StatusOK)
+		w.Write(v)
+	}).Methods("GET")
 
-	if limit == 0 {
-		return []Book{}, nil
-	}
+	router.HandleFunc("/books/volume/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		if id == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
 
-	for len(books) < limit {
-		resp, err := http.Get(fmt.Sprintf("https://www.googleapis.com/books/v1/volumes?q=%s", q))
+		book, err := getBookByVolumeID(r.Context(), id)
 		if err != nil {
-			return []Book{}, fmt.Errorf("making GET request: %v", err)
+			http.Error(w, fmt.Sprintf("getting book by volume id: %v", err), statusFor(err))
+			return
 		}
 
-		var response struct {
-			Items []struct {
-				BookInformation BookInformation `json:"volumeInfo"`
-			} `json:"items"`
+		v, err := json.Marshal(&book)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			return []Book{}, err
+		w.WriteHeader(http.StatusOK)
+		w.Write(v)
+	}).Methods("GET")
+
+	router.HandleFunc("/index/rebuild", func(w http.ResponseWriter, r *http.Request) {
+		if meiliClient == nil {
+			http.Error(w, "MEILI_HOST is not configured", http.StatusServiceUnavailable)
+			return
 		}
 
-		if len(response.Items) == 0 && len(books) == 0 {
-			return []Book{}, nil
-		} else if len(response.Items) == 0 {
-			return books, nil
+		var body struct {
+			Queries []string `json:"queries"`
 		}
 
-		for _, item := range response.Items {
-			if len(books) >= limit {
-				break
-			}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Queries) == 0 {
+			http.Error(w, "queries is required", http.StatusBadRequest)
+			return
+		}
 
-			if hasEmptyInformation(item.BookInformation) {
-				continue
-			}
+		upserted, err := rebuildIndex(r.Context(), body.Queries)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("rebuilding index: %v", err), http.StatusInternalServerError)
+			return
+		}
 
-			books = append(books, Book{
-				BookInformation: item.BookInformation,
-			})
+		v, err := json.Marshal(&struct {
+			Index    string `json:"index"`
+			Upserted int    `json:"upserted"`
+		}{
+			Index:    otherBooksIndex(),
+			Upserted: upserted,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-	}
 
-	return books, nil
-}
+		w.WriteHeader(http.StatusOK)
+		w.Write(v)
+	}).Methods("POST")
+
+	router.HandleFunc("/index/switch", func(w http.ResponseWriter, r *http.Request) {
+		if meiliClient == nil {
+			http.Error(w, "MEILI_HOST is not configured", http.StatusServiceUnavailable)
+			return
+		}
+
+		next := otherBooksIndex()
+
+		populated, err := indexIsPopulated(next)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("checking index %q: %v", next, err), http.StatusInternalServerError)
+			return
+		}
+
+		if !populated {
+			http.Error(w, fmt.Sprintf("index %q has not been rebuilt yet", next), http.StatusConflict)
+			return
+		}
+
+		useIndex.Store(next)
+
+		v, err := json.Marshal(&struct {
+			Index string `json:"index"`
+		}{
+			Index: next,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(v)
+	}).Methods("POST")
+
+	router.HandleFunc("/auth/login", handleAuthLogin).Methods("GET")
+	router.HandleFunc("/auth/callback", handleAuthCallback).Methods("GET")
+
+	shelves := router.PathPrefix("/shelves").Subrouter()
+	shelves.Use(requireAuth)
+
+	shelves.HandleFunc("/{name}/books/{volumeId}", func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		shelf, volumeID := vars["name"], vars["volumeId"]
+		email, _ := userEmailFromContext(r.Context())
+
+		has, err := shelfStore.HasBook(r.Context(), email, shelf, volumeID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-func hasEmptyInformation(bookInformation BookInformation) bool {
-	return bookInformation.Pages == 0 ||
-		bookInformation.Description == "" ||
-		len(bookInformation.Authors) == 0 ||
-		bookInformation.Images.Normal == "" ||
-		bookInformation.Images.Small == "" ||
-		bookInformation.Title == "" ||
-		len(bookInformation.Categories) == 0
+		if !has {
+			http.Error(w, "book not on shelf", http.StatusNotFound)
+			return
+		}
+
+		book, err := getBookByVolumeID(r.Context(), volumeID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("getting book by volume id: %v", err), statusFor(err))
+			return
+		}
+
+		v, err := json.Marshal(&book)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(v)
+	}).Methods("GET")
+
+	shelves.HandleFunc("/{name}/books/{volumeId}", func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		shelf, volumeID := vars["name"], vars["volumeId"]
+		email, _ := userEmailFromContext(r.Context())
+
+		if err := shelfStore.AddBook(r.Context(), email, shelf, volumeID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("POST")
+
+	shelves.HandleFunc("/{name}/books/{volumeId}", func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		shelf, volumeID := vars["name"], vars["volumeId"]
+		email, _ := userEmailFromContext(r.Context())
+
+		if err := shelfStore.RemoveBook(r.Context(), email, shelf, volumeID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	if err := http.ListenAndServe(":"+port, router); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync/atomic"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// meiliDocument is the shape a Book is flattened into for indexing. Meili
+// documents need a primary key, so we derive one from the volume's title
+// and authors since the Google Books API doesn't expose a stable ID here.
+type meiliDocument struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Authors     []string `json:"authors"`
+	Categories  []string `json:"categories"`
+	PageCount   int      `json:"pageCount"`
+}
+
+const (
+	booksIndexV1 = "books_v1"
+	booksIndexV2 = "books_v2"
+)
+
+var (
+	meiliClient *meilisearch.Client
+	// useIndex holds the UID of the index currently serving /books reads,
+	// flipped atomically by /index/switch so a rebuild of the other index
+	// can't be observed mid-write (blue/green).
+	useIndex atomic.Value
+)
+
+// initMeili connects to Meilisearch when MEILI_HOST is set and makes sure
+// both the v1 and v2 books indexes exist with the attributes /books and
+// /index/rebuild rely on. It is a no-op, leaving meiliClient nil, when
+// MEILI_HOST is unset.
+func initMeili() error {
+	host := os.Getenv("MEILI_HOST")
+	if host == "" {
+		return nil
+	}
+
+	meiliClient = meilisearch.NewClient(meilisearch.ClientConfig{
+		Host:   host,
+		APIKey: os.Getenv("MEILI_API_KEY"),
+	})
+
+	for _, uid := range []string{booksIndexV1, booksIndexV2} {
+		if err := ensureBooksIndex(uid); err != nil {
+			return fmt.Errorf("ensuring index %q: %w", uid, err)
+		}
+	}
+
+	useIndex.Store(booksIndexV1)
+
+	return nil
+}
+
+// ensureBooksIndex creates the index if it doesn't exist yet and applies
+// the searchable/filterable attributes the /books handler depends on.
+func ensureBooksIndex(uid string) error {
+	if _, err := meiliClient.GetIndex(uid); err != nil {
+		if _, err := meiliClient.CreateIndex(&meilisearch.IndexConfig{Uid: uid, PrimaryKey: "id"}); err != nil {
+			return err
+		}
+	}
+
+	index := meiliClient.Index(uid)
+
+	searchable := []string{"title", "authors", "description", "categories"}
+	if _, err := index.UpdateSearchableAttributes(&searchable); err != nil {
+		return err
+	}
+
+	filterable := []string{"authors", "categories", "pageCount"}
+	if _, err := index.UpdateFilterableAttributes(&filterable); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// indexIsPopulated reports whether uid has at least one document, so
+// /index/switch can refuse to flip live traffic onto an index that was
+// never rebuilt (or whose rebuild upserted nothing).
+func indexIsPopulated(uid string) (bool, error) {
+	stats, err := meiliClient.Index(uid).GetStats()
+	if err != nil {
+		return false, err
+	}
+
+	return stats.NumberOfDocuments > 0, nil
+}
+
+// currentBooksIndex returns the index currently flipped live by
+// /index/switch.
+func currentBooksIndex() string {
+	uid, _ := useIndex.Load().(string)
+	if uid == "" {
+		return booksIndexV1
+	}
+
+	return uid
+}
+
+// otherBooksIndex returns the index /index/rebuild should write to: the
+// one not currently serving traffic.
+func otherBooksIndex() string {
+	if currentBooksIndex() == booksIndexV1 {
+		return booksIndexV2
+	}
+
+	return booksIndexV1
+}
+
+// searchIndexedBooks searches the live Meili index for query, returning the
+// matching books scored through bookScorer (so min_score filtering and
+// sort=score apply to them too), the facet distribution by author/category,
+// and whether there was at least one hit. Indexed documents carry no image
+// links or language, so rules on those fields score an indexed book 0, and
+// a book that fails a required rule is still returned rather than dropped.
+// A miss (ok == false) tells the caller to fall through to Google Books.
+func searchIndexedBooks(query string, offset, limit int) (books []Book, facets map[string]map[string]int64, ok bool, err error) {
+	index := meiliClient.Index(currentBooksIndex())
+
+	resp, err := index.Search(query, &meilisearch.SearchRequest{
+		Offset: int64(offset),
+		Limit:  int64(limit),
+		Facets: []string{"authors", "categories"},
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if len(resp.Hits) == 0 {
+		return nil, nil, false, nil
+	}
+
+	for _, hit := range resp.Hits {
+		raw, err := json.Marshal(hit)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		var doc meiliDocument
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, nil, false, err
+		}
+
+		info := BookInformation{
+			Title:       doc.Title,
+			Description: doc.Description,
+			Authors:     doc.Authors,
+			Categories:  doc.Categories,
+			Pages:       doc.PageCount,
+		}
+
+		score, _ := bookScorer.Score(toFilterBook(info))
+
+		books = append(books, Book{BookInformation: info, Score: score})
+	}
+
+	return books, decodeFacetDistribution(resp.FacetDistribution), true, nil
+}
+
+// decodeFacetDistribution normalizes Meili's facetDistribution (a
+// map[string]map[string]int64 under the hood, but typed interface{} in the
+// client) into a concrete type the JSON response can embed directly.
+func decodeFacetDistribution(raw interface{}) map[string]map[string]int64 {
+	if raw == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+
+	var facets map[string]map[string]int64
+	if err := json.Unmarshal(b, &facets); err != nil {
+		return nil
+	}
+
+	return facets
+}
+
+// rebuildIndex pages through Google Books for each seed query and upserts
+// the results into the index not currently serving traffic, so the next
+// /index/switch call flips to freshly indexed data.
+func rebuildIndex(ctx context.Context, seedQueries []string) (int, error) {
+	uid := otherBooksIndex()
+	index := meiliClient.Index(uid)
+
+	var upserted int
+
+	for _, query := range seedQueries {
+		books, _, _, err := getBooks(ctx, query, 0, maxLimit)
+		if err != nil {
+			return upserted, fmt.Errorf("fetching seed query %q: %w", query, err)
+		}
+
+		docs := make([]meiliDocument, 0, len(books))
+		for _, book := range books {
+			docs = append(docs, toMeiliDocument(book.BookInformation))
+		}
+
+		if len(docs) == 0 {
+			continue
+		}
+
+		if _, err := index.AddDocuments(docs); err != nil {
+			return upserted, fmt.Errorf("upserting seed query %q: %w", query, err)
+		}
+
+		upserted += len(docs)
+	}
+
+	return upserted, nil
+}
+
+// toMeiliDocument derives a stable document ID from title+authors, since
+// Google Books volumes don't carry one we can reuse directly as a Meili
+// primary key.
+func toMeiliDocument(info BookInformation) meiliDocument {
+	return meiliDocument{
+		ID:          documentID(info),
+		Title:       info.Title,
+		Description: info.Description,
+		Authors:     info.Authors,
+		Categories:  info.Categories,
+		PageCount:   info.Pages,
+	}
+}
+
+func documentID(info BookInformation) string {
+	h := fnv.New32a()
+	h.Write([]byte(info.Title))
+	for _, author := range info.Authors {
+		h.Write([]byte(author))
+	}
+
+	return fmt.Sprintf("%x", h.Sum32())
+}
@@ -0,0 +1,40 @@
+package main
+
+import "github.com/mateoferrari97/GoogleBooks/filter"
+
+type Book struct {
+	BookInformation BookInformation `json:"book_information"`
+	Score           float64         `json:"score"`
+}
+
+type BookInformation struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Authors     []string `json:"authors"`
+	Categories  []string `json:"categories"`
+	Pages       int      `json:"pageCount"`
+	Language    string   `json:"language,omitempty"`
+	Images      struct {
+		Small  string `json:"smallThumbnail"`
+		Normal string `json:"thumbnail"`
+	} `json:"imageLinks"`
+	IndustryIdentifiers []struct {
+		Type       string `json:"type"`
+		Identifier string `json:"identifier"`
+	} `json:"industryIdentifiers,omitempty"`
+}
+
+// toFilterBook adapts a BookInformation to the shape the filter package
+// scores, keeping filter free of a dependency back on this package.
+func toFilterBook(info BookInformation) filter.Book {
+	return filter.Book{
+		Title:       info.Title,
+		Description: info.Description,
+		Authors:     info.Authors,
+		Categories:  info.Categories,
+		Pages:       info.Pages,
+		Language:    info.Language,
+		ImageSmall:  info.Images.Small,
+		ImageNormal: info.Images.Normal,
+	}
+}
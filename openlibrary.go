@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// openLibraryBook is the subset of the OpenLibrary "Books API" response
+// (jscmd=data) we merge into a BookInformation.
+type openLibraryBook struct {
+	Title   string `json:"title"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Subjects []struct {
+		Name string `json:"name"`
+	} `json:"subjects"`
+	NumberOfPages int `json:"number_of_pages"`
+	Cover         struct {
+		Small  string `json:"small"`
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+	Excerpts []struct {
+		Text string `json:"text"`
+	} `json:"excerpts"`
+}
+
+// enrichFromOpenLibrary fills the gaps left by Google Books in info using
+// OpenLibrary's Books API, keyed by ISBN. Any field already populated on
+// info is left untouched; enrichFromOpenLibrary only adds what's missing.
+// Lookup failures are swallowed, since the caller already has a (partial)
+// Google Books result to fall back to.
+func enrichFromOpenLibrary(ctx context.Context, isbn string, info BookInformation) BookInformation {
+	ctx, cancel := context.WithTimeout(ctx, upstreamTimeout())
+	defer cancel()
+
+	bibkey := fmt.Sprintf("ISBN:%s", isbn)
+
+	resp, err := doUpstreamGET(ctx, fmt.Sprintf(
+		"https://openlibrary.org/api/books?bibkeys=%s&format=json&jscmd=data", bibkey,
+	))
+	if err != nil {
+		return info
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return info
+	}
+
+	var response map[string]openLibraryBook
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return info
+	}
+
+	ol, ok := response[bibkey]
+	if !ok {
+		return info
+	}
+
+	if info.Title == "" {
+		info.Title = ol.Title
+	}
+
+	if info.Description == "" && len(ol.Excerpts) > 0 {
+		info.Description = ol.Excerpts[0].Text
+	}
+
+	if len(info.Authors) == 0 {
+		for _, a := range ol.Authors {
+			info.Authors = append(info.Authors, a.Name)
+		}
+	}
+
+	if len(info.Categories) == 0 {
+		for _, s := range ol.Subjects {
+			info.Categories = append(info.Categories, s.Name)
+		}
+	}
+
+	if info.Pages == 0 {
+		info.Pages = ol.NumberOfPages
+	}
+
+	if info.Images.Normal == "" {
+		info.Images.Normal = ol.Cover.Medium
+	}
+
+	if info.Images.Small == "" {
+		info.Images.Small = ol.Cover.Small
+	}
+
+	return info
+}
+
+// isbnFromBookInformation pulls an ISBN-13 (preferring it over ISBN-10) out
+// of a Google Books industryIdentifiers list, for use as the OpenLibrary
+// lookup key.
+func isbnFromBookInformation(info BookInformation) string {
+	var isbn10 string
+
+	for _, id := range info.IndustryIdentifiers {
+		if id.Type == "ISBN_13" {
+			return id.Identifier
+		}
+
+		if id.Type == "ISBN_10" {
+			isbn10 = id.Identifier
+		}
+	}
+
+	return isbn10
+}
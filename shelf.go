@@ -0,0 +1,13 @@
+package main
+
+import "context"
+
+// ShelfStore persists a user's personal bookshelves, keyed by the owner's
+// email, a shelf name, and a Google Books volume ID. newShelfStore (see
+// shelf_sqlite.go and shelf_firestore.go) picks the implementation at
+// compile time via the firestore build tag.
+type ShelfStore interface {
+	AddBook(ctx context.Context, userEmail, shelf, volumeID string) error
+	RemoveBook(ctx context.Context, userEmail, shelf, volumeID string) error
+	HasBook(ctx context.Context, userEmail, shelf, volumeID string) (bool, error)
+}
@@ -0,0 +1,63 @@
+//go:build firestore
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newShelfStore connects to Firestore using GOOGLE_CLOUD_PROJECT. Building
+// with -tags firestore swaps this in for the SQLite default in
+// shelf_sqlite.go.
+func newShelfStore() (ShelfStore, error) {
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if projectID == "" {
+		return nil, fmt.Errorf("GOOGLE_CLOUD_PROJECT must be set to use the firestore shelf store")
+	}
+
+	client, err := firestore.NewClient(context.Background(), projectID)
+	if err != nil {
+		return nil, fmt.Errorf("creating firestore client: %w", err)
+	}
+
+	return &firestoreShelfStore{client: client}, nil
+}
+
+type firestoreShelfStore struct {
+	client *firestore.Client
+}
+
+func (s *firestoreShelfStore) docRef(userEmail, shelf, volumeID string) *firestore.DocumentRef {
+	return s.client.Collection("shelves").Doc(userEmail).Collection(shelf).Doc(volumeID)
+}
+
+func (s *firestoreShelfStore) AddBook(ctx context.Context, userEmail, shelf, volumeID string) error {
+	_, err := s.docRef(userEmail, shelf, volumeID).Set(ctx, map[string]interface{}{
+		"user_email": userEmail,
+		"volume_id":  volumeID,
+	})
+	return err
+}
+
+func (s *firestoreShelfStore) RemoveBook(ctx context.Context, userEmail, shelf, volumeID string) error {
+	_, err := s.docRef(userEmail, shelf, volumeID).Delete(ctx)
+	return err
+}
+
+func (s *firestoreShelfStore) HasBook(ctx context.Context, userEmail, shelf, volumeID string) (bool, error) {
+	_, err := s.docRef(userEmail, shelf, volumeID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
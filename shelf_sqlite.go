@@ -0,0 +1,77 @@
+//go:build !firestore
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	_ "modernc.org/sqlite"
+)
+
+// newShelfStore opens (creating if needed) the SQLite-backed shelf store.
+// The database path is taken from SHELF_DB_PATH, defaulting to a local
+// file so the module keeps working with zero configuration. This is the
+// default ShelfStore implementation; build with -tags firestore to use
+// firestoreShelfStore instead.
+func newShelfStore() (ShelfStore, error) {
+	path := os.Getenv("SHELF_DB_PATH")
+	if path == "" {
+		path = "shelves.db"
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite db: %w", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS shelf_books (
+	user_email TEXT NOT NULL,
+	shelf      TEXT NOT NULL,
+	volume_id  TEXT NOT NULL,
+	PRIMARY KEY (user_email, shelf, volume_id)
+)`
+
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+
+	return &sqliteShelfStore{db: db}, nil
+}
+
+type sqliteShelfStore struct {
+	db *sql.DB
+}
+
+func (s *sqliteShelfStore) AddBook(ctx context.Context, userEmail, shelf, volumeID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO shelf_books (user_email, shelf, volume_id) VALUES (?, ?, ?)`,
+		userEmail, shelf, volumeID)
+	return err
+}
+
+func (s *sqliteShelfStore) RemoveBook(ctx context.Context, userEmail, shelf, volumeID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM shelf_books WHERE user_email = ? AND shelf = ? AND volume_id = ?`,
+		userEmail, shelf, volumeID)
+	return err
+}
+
+func (s *sqliteShelfStore) HasBook(ctx context.Context, userEmail, shelf, volumeID string) (bool, error) {
+	var exists int
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT 1 FROM shelf_books WHERE user_email = ? AND shelf = ? AND volume_id = ?`,
+		userEmail, shelf, volumeID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}